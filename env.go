@@ -3,28 +3,36 @@ Package env provides functions to load environment variables from a .env file in
 environment variables, and to parse them into a given struct.
 
 It supports several tags:
-`default`- provides the default variable value.
-`env` - provides variable name that allows overriding the default variable.
+`default`  - provides the default variable value.
+`env`      - provides variable name that allows overriding the default variable.
+`required` - the field must have a value from a source; a default alone does not satisfy it.
+`notEmpty` - the field's resolved value must be non-empty.
+`expand`   - the value is expanded for ${VAR}, $VAR and ${VAR:-default} references before use.
+`file`     - the value is treated as a path, and the field is set to that file's contents;
+useful for Docker/Kubernetes secrets mounted as files (e.g. DB_PASSWORD_FILE).
+`flag`     - registers the field as a command-line flag (e.g. `flag:"port,p"` for a
+long and short name) for use with Loader.ParseFlags; see `usage` for its help text.
 
 If tags are not provided, field names in the struct are automatically transformed
 to the conventional SNAKE_CASE with parent struct prefix to match environment variable.
-In case, a variable or the given struct field is not found,
-and default value is not provided; an error is returned.
+
+Parsing does not stop at the first failing field: every failure is collected into
+a *ParseError, whose Fields describe what went wrong per field, and which supports
+errors.As and errors.Is via Unwrap.
+
+Parsing is driven by a Loader and an ordered chain of Source providers
+(dotenv files, the OS environment, JSON/YAML/TOML files, explicit maps).
+ParseTo is a thin wrapper around the default chain of a dotenv file
+followed by the OS environment; use New to compose other sources.
 */
 package env
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
-	"io/fs"
-	"log"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 )
 
 const (
@@ -39,142 +47,62 @@ func ParseTo(dst any, envPaths ...string) error {
 		envPaths = []string{".env"}
 	}
 
-	for i := range envPaths {
-		if err := Load(envPaths[i]); err != nil {
-			return err
-		}
-	}
-
-	if err := parseTo(dst, ""); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// Load loads the environment variables from a .env file into the system's.
-func Load(pth string) error {
-	env, err := os.Open(pth)
-	if err != nil && !errors.Is(err, fs.ErrNotExist) {
-		return fmt.Errorf("opening dotenv file: %w", err)
-	}
-
-	defer func() {
-		if err := env.Close(); err != nil {
-			log.Printf("closing dotenv file: %v", err)
-		}
-	}()
-
-	buf := bufio.NewScanner(env)
-	for buf.Scan() {
-		line := buf.Text()
-		if line == "" {
-			continue
-		}
-
-		key, val := parseLine(line)
-
-		if err := os.Setenv(key, val); err != nil {
-			return fmt.Errorf("setting %s[%s]: %w", key, val, err)
-		}
+	sources := make([]Source, 0, len(envPaths)+1)
+	for _, p := range envPaths {
+		sources = append(sources, DotEnv(p))
 	}
 
-	if err := buf.Err(); err != nil {
-		return fmt.Errorf("reading env file: %w", err)
-	}
+	sources = append(sources, OSEnv())
 
-	return nil
+	return New().From(sources...).Parse(dst)
 }
 
-// parseTo fills the struct fields
-// assigning the values from sources.
-func parseTo(dst any, prefix string) error {
-	v := reflect.ValueOf(dst).Elem()
-	t := v.Type()
-
-	for i := 0; i < t.NumField(); i++ {
-		fieldVal := v.Field(i)
-		fieldType := t.Field(i)
-
-		fieldName := prefix + fieldType.Name
-		if fieldType.Type.Kind() == reflect.Struct {
-			// recursive call is needed for nested structs.
-			if err := parseTo(fieldVal.Addr().Interface(), fieldName); err != nil {
-				return err
-			}
-
-			continue
-		}
-
-		val := getFieldValue(fieldType, fieldName)
-		if val == "" && prefix != "" {
-			return fmt.Errorf("no value for field: %s", fieldType.Name)
-		}
-
-		if err := setFieldValue(fieldType.Type, fieldVal, val); err != nil {
-			return err
-		}
+// camelToSnake converts a CamelCase string to SNAKE_CASE, keeping runs of
+// uppercase letters (acronyms) and digits together as their own words; see
+// splitWords.
+func camelToSnake(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w)
 	}
 
-	return nil
+	return strings.Join(words, "_")
 }
 
-// getFieldValue gets the value for a field from different sources:
-// the environment variables or the `default` struct tag values.
-func getFieldValue(fieldType reflect.StructField, fieldName string) string {
-	envTag := fieldType.Tag.Get(tagEnv)
-	if val, ok := os.LookupEnv(envTag); ok {
-		return val
-	}
-
-	if val, ok := os.LookupEnv(camelToSnake(fieldName)); ok {
-		return val
+// setFieldValue sets the value for a struct field according to its type
+// and tags. It first tries an Unmarshaler implementation and then a parser
+// registered on parsers, dereferencing and allocating pointer fields as
+// needed, before falling through to the built-in kinds. parsers may be nil.
+//
+//nolint:cyclop
+func setFieldValue(fieldType reflect.StructField, fieldVal reflect.Value, val string, parsers map[reflect.Type]func(string) (any, error)) error {
+	if u, ok := asUnmarshaler(fieldVal); ok {
+		return u.UnmarshalEnv(val)
 	}
 
-	val := fieldType.Tag.Get(tagDefault)
+	if parse, ok := parsers[fieldType.Type]; ok {
+		parsed, err := parse(val)
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", fieldType.Name, err)
+		}
 
-	return val
-}
+		fieldVal.Set(reflect.ValueOf(parsed))
 
-// parseLine parses a have from the .env file or value from os.Environ().
-func parseLine(line string) (string, string) {
-	i := strings.Index(line, "=")
-	if i <= 0 {
-		return "", ""
+		return nil
 	}
 
-	return line[:i], line[i+1:]
-}
-
-// camelToSnake converts a CamelCase string to SNAKE_CASE.
-func camelToSnake(s string) string {
-	var (
-		parts []string
-		start int
-	)
-
-	for i, r := range s {
-		if unicode.IsUpper(r) && i != 0 {
-			parts = append(parts, s[start:i])
-			start = i
+	if fieldType.Type.Kind() == reflect.Ptr {
+		if fieldVal.IsNil() {
+			fieldVal.Set(reflect.New(fieldType.Type.Elem()))
 		}
-	}
 
-	parts = append(parts, s[start:])
+		elem := fieldType
+		elem.Type = fieldType.Type.Elem()
 
-	for i, p := range parts {
-		parts[i] = strings.ToUpper(p)
+		return setFieldValue(elem, fieldVal.Elem(), val, parsers)
 	}
 
-	return strings.Join(parts, "_")
-}
-
-// setFieldValue sets the value for a struct field according to a field type.
-//
-//nolint:cyclop
-func setFieldValue(fieldType reflect.Type, fieldVal reflect.Value, val string) error {
-	switch fieldType.Kind() {
-	case reflect.TypeOf(time.Duration(0)).Kind():
+	if fieldType.Type == reflect.TypeOf(time.Duration(0)) {
 		val, err := time.ParseDuration(val)
 		if err != nil {
 			return fmt.Errorf("parsing duration: %w", err)
@@ -182,8 +110,12 @@ func setFieldValue(fieldType reflect.Type, fieldVal reflect.Value, val string) e
 
 		fieldVal.Set(reflect.ValueOf(val))
 
+		return nil
+	}
+
+	switch fieldType.Type.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		val, err := strconv.ParseInt(val, 0, fieldType.Bits())
+		val, err := strconv.ParseInt(val, 0, fieldType.Type.Bits())
 		if err != nil {
 			return fmt.Errorf("parsing integer: %w", err)
 		}
@@ -191,7 +123,7 @@ func setFieldValue(fieldType reflect.Type, fieldVal reflect.Value, val string) e
 		fieldVal.SetInt(val)
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		val, err := strconv.ParseUint(val, 0, fieldType.Bits())
+		val, err := strconv.ParseUint(val, 0, fieldType.Type.Bits())
 		if err != nil {
 			return fmt.Errorf("parsing unsigned integer: %w", err)
 		}
@@ -218,14 +150,13 @@ func setFieldValue(fieldType reflect.Type, fieldVal reflect.Value, val string) e
 		fieldVal.SetString(val)
 
 	case reflect.Slice:
-		if fieldType.Elem().Kind() != reflect.String {
-			return fmt.Errorf("unsupported slice kind: %s", fieldType.Elem().Kind())
-		}
+		return setSliceValue(fieldType, fieldVal, val, parsers)
 
-		fieldVal.Set(reflect.ValueOf(strings.Split(val, ",")))
+	case reflect.Map:
+		return setMapValue(fieldType.Type, fieldVal, val)
 
 	default:
-		return fmt.Errorf("unsupported field type: %s", fieldType.Name())
+		return fmt.Errorf("unsupported field type: %s", fieldType.Name)
 	}
 
 	return nil
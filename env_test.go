@@ -16,7 +16,9 @@ func TestParseTo(t *testing.T) {
 		Home    string `env:"HOME"`
 		Default string `default:"default"`
 		Empty   int
-		Nested  struct{ Value string }
+		Nested  struct {
+			Value string `notEmpty:"true"`
+		}
 	}
 
 	tests := map[string]struct {
@@ -25,8 +27,10 @@ func TestParseTo(t *testing.T) {
 		wantErr error
 	}{
 		"All environment variables present": {
-			vars:    map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
-			want:    &config{Home: "/home/test", Default: "default", Empty: 10, Nested: struct{ Value string }{"nested"}},
+			vars: map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
+			want: &config{Home: "/home/test", Default: "default", Empty: 10, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
 			wantErr: nil,
 		},
 		"No environment variables, except default present": {
@@ -50,8 +54,10 @@ func TestParseTo(t *testing.T) {
 			wantErr: fmt.Errorf("parsing integer: strconv.ParseInt: parsing \"invalid\": invalid syntax"),
 		},
 		"Nested struct environment variable set": {
-			vars:    map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
-			want:    &config{Home: "/home/test", Default: "default", Empty: 10, Nested: struct{ Value string }{"nested"}},
+			vars: map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
+			want: &config{Home: "/home/test", Default: "default", Empty: 10, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
 			wantErr: nil,
 		},
 	}
@@ -63,7 +69,7 @@ func TestParseTo(t *testing.T) {
 			defer teardown()
 
 			var cfg config
-			err = parseTo(&cfg, "")
+			err = New().From(OSEnv()).Parse(&cfg)
 
 			if tc.wantErr != nil {
 				require.Error(t, err, tc.wantErr)
@@ -76,50 +82,6 @@ func TestParseTo(t *testing.T) {
 	}
 }
 
-func TestLoadEnv(t *testing.T) {
-	tests := map[string]struct {
-		vars    map[string]string
-		wantErr error
-	}{
-		"Valid environment file": {
-			vars:    map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
-			wantErr: nil,
-		},
-		"Empty variables": {
-			vars:    map[string]string{"": ""},
-			wantErr: errors.New("setting []: setenv: The parameter is incorrect"),
-		},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			file, teardown, err := setupEnvFile(t, tc.vars)
-			require.NoError(t, err)
-			defer teardown()
-
-			teardown, err = setupEnv(t, tc.vars)
-			if tc.wantErr != nil {
-				require.Error(t, err)
-				return
-			}
-
-			require.NoError(t, err)
-			defer teardown()
-
-			err = Load(file.Name())
-			if tc.wantErr != nil {
-				require.Error(t, err)
-				return
-			}
-
-			require.NoError(t, err)
-			for k, v := range tc.vars {
-				require.Equal(t, v, os.Getenv(k))
-			}
-		})
-	}
-}
-
 func TestSetFieldValue(t *testing.T) {
 	tests := map[string]struct {
 		field   interface{}
@@ -130,6 +92,7 @@ func TestSetFieldValue(t *testing.T) {
 		"Duration":         {time.Duration(0), "1h", time.Hour, nil},
 		"Invalid Duration": {time.Duration(0), "invalid", nil, errors.New("parsing duration: time: invalid duration \"invalid\"")},
 		"Int":              {0, "123", 123, nil},
+		"Int64":            {int64(0), "123", int64(123), nil},
 		"Invalid Int":      {0, "invalid", nil, errors.New("parsing integer: strconv.ParseInt: parsing \"invalid\": invalid syntax")},
 		"Float":            {0.0, "1.23", 1.23, nil},
 		"Invalid Float":    {0.0, "invalid", nil, errors.New("parsing float: strconv.ParseFloat: parsing \"invalid\": invalid syntax")},
@@ -141,7 +104,8 @@ func TestSetFieldValue(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			fieldVal := reflect.New(reflect.TypeOf(tc.field)).Elem()
-			err := setFieldValue(reflect.TypeOf(tc.field), fieldVal, tc.have)
+			fieldType := reflect.StructField{Name: "Field", Type: reflect.TypeOf(tc.field)}
+			err := setFieldValue(fieldType, fieldVal, tc.have, nil)
 
 			if tc.wantErr != nil {
 				require.Error(t, err, tc.wantErr)
@@ -164,6 +128,9 @@ func TestCamelToSnake(t *testing.T) {
 		{name: "Camel case", have: "camelCase", want: "CAMEL_CASE"},
 		{name: "Snake case", have: "snake_case", want: "SNAKE_CASE"},
 		{name: "Lowercase", have: "lowercase", want: "LOWERCASE"},
+		{name: "Acronym run", have: "HTTPPort", want: "HTTP_PORT"},
+		{name: "Acronym at end", have: "UserID", want: "USER_ID"},
+		{name: "Digit boundary", have: "Http2Port", want: "HTTP_2_PORT"},
 	}
 
 	for _, tc := range tests {
@@ -174,28 +141,6 @@ func TestCamelToSnake(t *testing.T) {
 	}
 }
 
-func TestParseLine(t *testing.T) {
-	tests := map[string]struct {
-		have      string
-		wantKey   string
-		wantValue string
-	}{
-		"Line with key-value pair":         {have: "KEY=value", wantKey: "KEY", wantValue: "value"},
-		"Line with empty key":              {have: "EMPTY_KEY=", wantKey: "EMPTY_KEY", wantValue: ""},
-		"Line with empty value":            {have: "=EMPTY_VALUE", wantKey: "", wantValue: ""},
-		"Line without equal sign":          {have: "NO_EQUAL_SIGN", wantKey: "", wantValue: ""},
-		"Another have with key-value pair": {have: "ANOTHER_CASE=another_value", wantKey: "ANOTHER_CASE", wantValue: "another_value"},
-	}
-
-	for name, tc := range tests {
-		t.Run(name, func(t *testing.T) {
-			key, value := parseLine(tc.have)
-			require.Equal(t, tc.wantKey, key)
-			require.Equal(t, tc.wantValue, value)
-		})
-	}
-}
-
 func setupEnv(t *testing.T, vars map[string]string) (func(), error) {
 	t.Helper()
 
@@ -219,37 +164,3 @@ func setupEnv(t *testing.T, vars map[string]string) (func(), error) {
 
 	return teardown, nil
 }
-
-func setupEnvFile(t *testing.T, vars map[string]string) (*os.File, func(), error) {
-	t.Helper()
-
-	file, err := os.CreateTemp(os.TempDir(), "test.env")
-	if err != nil {
-		return nil, func() {}, err
-	}
-
-	if vars == nil {
-		return file, func() {}, nil
-	}
-
-	for key, value := range vars {
-		_, err := file.WriteString(fmt.Sprintf("%s=%s\n", key, value))
-		if err != nil {
-			return nil, nil, err
-		}
-	}
-
-	teardown := func() {
-		err := file.Close()
-		if err != nil {
-			t.Error("createEnvFile: close:", err)
-		}
-
-		err = os.RemoveAll(file.Name())
-		if err != nil {
-			t.Error("createEnvFile: teardown:", err)
-		}
-	}
-
-	return file, teardown, nil
-}
@@ -0,0 +1,125 @@
+package env
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	tagFlag  = "flag"
+	tagUsage = "usage"
+)
+
+// flagValue is a flag.Value that records the raw string passed on the
+// command line without converting it, so setFieldValue can do the actual
+// conversion the same way it does for every other Source.
+type flagValue struct {
+	val    string
+	set    bool
+	isBool bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.val
+}
+
+func (v *flagValue) Set(s string) error {
+	v.val = s
+	v.set = true
+
+	return nil
+}
+
+// IsBoolFlag reports whether the field behind v is a bool (or *bool), so
+// flag.FlagSet lets its flag be passed as a bare "-name" instead of
+// requiring "-name=true", matching the convention every other bool flag in
+// Go follows.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+// registerFlags walks v's fields, recursing into nested structs the same
+// way parseToSources does, and registers a flagValue on fs for every field
+// tagged `flag:"name,alias"`, keyed in values by its struct path so
+// flagSource can find it again after fs.Parse. A name used by more than
+// one field is reported as an error.
+func registerFlags(fs *flag.FlagSet, v reflect.Value, path []string, values map[string]*flagValue, parsers map[reflect.Type]func(string) (any, error)) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldVal := v.Field(i)
+		fieldType := t.Field(i)
+
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		fieldPath := append(append([]string{}, path...), fieldType.Name)
+
+		if fieldType.Type.Kind() == reflect.Struct && !isLeafStruct(fieldType.Type, fieldVal, parsers) {
+			if err := registerFlags(fs, fieldVal, fieldPath, values, parsers); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		tag := fieldType.Tag.Get(tagFlag)
+		if tag == "" {
+			continue
+		}
+
+		fv := &flagValue{isBool: isBoolType(fieldType.Type)}
+
+		for _, name := range strings.Split(tag, ",") {
+			name = strings.TrimSpace(name)
+			if fs.Lookup(name) != nil {
+				return fmt.Errorf("flag %s: already registered", name)
+			}
+
+			fs.Var(fv, name, fieldType.Tag.Get(tagUsage))
+		}
+
+		values[strings.Join(fieldPath, "\x00")] = fv
+	}
+
+	return nil
+}
+
+// isBoolType reports whether t is bool or a pointer to bool, mirroring the
+// pointer dereference setFieldValue does when actually converting the
+// value.
+func isBoolType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Bool
+}
+
+// flagSource resolves a field's struct path to the flagValue registered
+// for it by registerFlags, if the flag was actually passed on the command
+// line. It implements PathSource so a field's flag is still consulted even
+// when the field also carries an explicit `env` tag.
+type flagSource struct {
+	values map[string]*flagValue
+}
+
+func (s *flagSource) Lookup(path []string) (string, bool) {
+	fv, ok := s.values[strings.Join(path, "\x00")]
+	if !ok || !fv.set {
+		return "", false
+	}
+
+	return fv.val, true
+}
+
+func (s *flagSource) LookupPath(path []string) (string, bool) {
+	return s.Lookup(path)
+}
@@ -0,0 +1,106 @@
+package env
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_ParseFlags(t *testing.T) {
+	type config struct {
+		Port int    `flag:"port,p" usage:"listen port"`
+		Host string `env:"HOST"`
+	}
+
+	var cfg config
+	err := New().ParseFlags([]string{"-p", "9090"}, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 9090, cfg.Port)
+
+	cfg = config{}
+	err = New().ParseFlags([]string{"-port", "9091"}, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 9091, cfg.Port)
+}
+
+func TestLoader_ParseFlags_Precedence(t *testing.T) {
+	type config struct {
+		Port int `flag:"port" default:"8080"`
+	}
+
+	var cfg config
+	err := New().From(Map(map[string]string{"PORT": "80"})).ParseFlags(nil, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 80, cfg.Port, "flag not passed, falls back to the loader's sources")
+
+	cfg = config{}
+	err = New().From(Map(map[string]string{"PORT": "80"})).ParseFlags([]string{"-port", "90"}, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 90, cfg.Port, "flag passed, wins over the loader's sources")
+
+	cfg = config{}
+	err = New().ParseFlags(nil, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 8080, cfg.Port, "neither flag nor source has it, falls back to default")
+}
+
+func TestLoader_ParseFlags_Nested(t *testing.T) {
+	type config struct {
+		Database struct {
+			URL string `flag:"db-url"`
+		}
+	}
+
+	var cfg config
+	require.NoError(t, New().ParseFlags([]string{"-db-url", "postgres://localhost"}, &cfg))
+	require.Equal(t, "postgres://localhost", cfg.Database.URL)
+}
+
+func TestLoader_ParseFlags_DuplicateName(t *testing.T) {
+	type config struct {
+		A string `flag:"name"`
+		B string `flag:"name"`
+	}
+
+	var cfg config
+	err := New().ParseFlags(nil, &cfg)
+	require.ErrorContains(t, err, "already registered")
+}
+
+func TestLoader_ParseFlags_InvalidArg(t *testing.T) {
+	type config struct {
+		Port int `flag:"port"`
+	}
+
+	var cfg config
+	err := New().ParseFlags([]string{"-unknown"}, &cfg)
+	require.Error(t, err)
+	require.False(t, errors.As(err, new(*ParseError)))
+}
+
+func TestLoader_ParseFlags_EnvTagAndFlagTag(t *testing.T) {
+	type config struct {
+		Port int `env:"PORT" flag:"port,p"`
+	}
+
+	var cfg config
+	err := New().ParseFlags([]string{"-p", "9090"}, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 9090, cfg.Port, "a flag must win even when the field also has an env tag")
+
+	cfg = config{}
+	err = New().From(Map(map[string]string{"PORT": "80"})).ParseFlags(nil, &cfg)
+	require.NoError(t, err)
+	require.Equal(t, 80, cfg.Port, "falls back to the env tag when the flag isn't passed")
+}
+
+func TestLoader_ParseFlags_Bool(t *testing.T) {
+	type config struct {
+		Verbose bool `flag:"verbose,v"`
+	}
+
+	var cfg config
+	require.NoError(t, New().ParseFlags([]string{"-v"}, &cfg))
+	require.True(t, cfg.Verbose, "a bool flag must accept the bare -v form like every other Go bool flag")
+}
@@ -0,0 +1,322 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+)
+
+// LoadOptions configures how LoadWithOptions parses a .env file and applies
+// it to the process environment.
+type LoadOptions struct {
+	// DisableExpansion turns off ${VAR}, $VAR and ${VAR:-default} expansion,
+	// leaving values exactly as they appear in the file.
+	DisableExpansion bool
+
+	// Overload makes values from the file replace variables that are
+	// already set in the process environment. By default, an already-set
+	// variable is left untouched (first-wins).
+	Overload bool
+
+	// IgnoreMissing treats a missing file as empty instead of returning an
+	// error.
+	IgnoreMissing bool
+}
+
+// Load loads the environment variables from a .env file into the process
+// environment. Variables already present in the environment are left
+// untouched; use LoadWithOptions with Overload to replace them. A missing
+// file is not an error.
+func Load(pth string) error {
+	return LoadWithOptions(pth, LoadOptions{IgnoreMissing: true})
+}
+
+// LoadWithOptions loads the environment variables from a .env file into the
+// process environment, applying opts.
+//
+// The file supports "export KEY=VALUE" prefixes, single- and double-quoted
+// values, and inline "#" comments outside of quotes. Double-quoted values
+// resolve \n, \t, \" and \\ escapes and may span multiple lines up to the
+// closing quote; single-quoted values are taken literally. Unless
+// opts.DisableExpansion is set, values also expand ${VAR}, $VAR and
+// ${VAR:-default} references, resolved first against values already set
+// earlier in the same file, then against the process environment.
+func LoadWithOptions(pth string, opts LoadOptions) error {
+	vars, err := parseDotEnv(pth, opts)
+	if err != nil {
+		return err
+	}
+
+	for key, val := range vars {
+		if !opts.Overload {
+			if _, ok := os.LookupEnv(key); ok {
+				continue
+			}
+		}
+
+		if err := os.Setenv(key, val); err != nil {
+			return fmt.Errorf("setting %s[%s]: %w", key, val, err)
+		}
+	}
+
+	return nil
+}
+
+// parseDotEnv parses pth the way LoadWithOptions does, but returns the
+// resulting key/value pairs instead of applying them to the process
+// environment, so a caller such as dotEnvSource can keep them private. A
+// missing file is treated as empty when opts.IgnoreMissing is set.
+func parseDotEnv(pth string, opts LoadOptions) (map[string]string, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && opts.IgnoreMissing {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("opening dotenv file: %w", err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Printf("closing dotenv file: %v", err)
+		}
+	}()
+
+	vars := make(map[string]string)
+
+	buf := bufio.NewScanner(f)
+	for buf.Scan() {
+		line := strings.TrimSpace(buf.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, val, quoted, expandable, err := splitKeyValue(line, buf)
+		if err != nil {
+			return nil, fmt.Errorf("parsing dotenv line: %w", err)
+		}
+
+		if key == "" {
+			continue
+		}
+
+		if !quoted {
+			val = stripInlineComment(val)
+		}
+
+		if expandable && !opts.DisableExpansion {
+			val = expand(val, vars)
+		}
+
+		vars[key] = val
+	}
+
+	if err := buf.Err(); err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+
+	return vars, nil
+}
+
+// splitKeyValue splits a trimmed dotenv line into its key and raw value,
+// first stripping an optional "export " prefix. When the value starts with
+// a quote, it consumes further lines from buf until the matching closing
+// quote is found. quoted reports whether the value was wrapped in quotes
+// (so the caller must not strip an inline comment out of it), and
+// expandable reports whether it may still carry a variable reference: true
+// for unquoted and double-quoted values, false for single-quoted (literal)
+// values.
+func splitKeyValue(line string, buf *bufio.Scanner) (key, val string, quoted, expandable bool, err error) {
+	line = strings.TrimPrefix(line, "export ")
+
+	i := strings.Index(line, "=")
+	if i <= 0 {
+		return "", "", false, false, nil
+	}
+
+	key = strings.TrimSpace(line[:i])
+	raw := strings.TrimSpace(line[i+1:])
+
+	if raw == "" {
+		return key, "", false, true, nil
+	}
+
+	switch raw[0] {
+	case '"':
+		val, err = readQuoted(raw[1:], buf, '"', true)
+		quoted, expandable = true, true
+	case '\'':
+		val, err = readQuoted(raw[1:], buf, '\'', false)
+		quoted = true
+	default:
+		val = raw
+		expandable = true
+	}
+
+	return key, val, quoted, expandable, err
+}
+
+// readQuoted reads a quoted value whose opening quote has already been
+// consumed, pulling further lines from buf until the matching closing quote
+// is found. When unescape is true, \n, \t, \" and \\ escapes are resolved.
+func readQuoted(body string, buf *bufio.Scanner, quote byte, unescape bool) (string, error) {
+	for {
+		if end, ok := findUnescaped(body, quote); ok {
+			val := body[:end]
+			if unescape {
+				val = unescapeValue(val)
+			}
+
+			return val, nil
+		}
+
+		if !buf.Scan() {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+
+		body += "\n" + buf.Text()
+	}
+}
+
+// findUnescaped returns the index of the first unescaped occurrence of b in
+// s.
+func findUnescaped(s string, b byte) (int, bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			continue
+		}
+
+		if s[i] == b {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// unescapeValue resolves \n, \t, \" and \\ escape sequences in a
+// double-quoted value.
+func unescapeValue(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i+1])
+			}
+
+			i++
+
+			continue
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// stripInlineComment removes a trailing "# ..." comment from an unquoted
+// value: a "#" starts a comment when it's preceded by whitespace, or when
+// it's the first character and is followed by whitespace or nothing, so
+// values like "#336699" or "http://host/cb#token" are left untouched.
+func stripInlineComment(val string) string {
+	for i := 0; i < len(val); i++ {
+		if val[i] != '#' {
+			continue
+		}
+
+		if i == 0 && (len(val) == 1 || val[1] == ' ' || val[1] == '\t') ||
+			i > 0 && (val[i-1] == ' ' || val[i-1] == '\t') {
+			val = val[:i]
+			break
+		}
+	}
+
+	return strings.TrimSpace(val)
+}
+
+// expand resolves ${VAR}, $VAR and ${VAR:-default} references in val,
+// looking them up first in local, then in the process environment.
+func expand(val string, local map[string]string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(val); i++ {
+		c := val[i]
+		if c != '$' || i == len(val)-1 {
+			b.WriteByte(c)
+			continue
+		}
+
+		if val[i+1] == '{' {
+			end := strings.IndexByte(val[i+2:], '}')
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+
+			b.WriteString(resolveExpr(val[i+2:i+2+end], local))
+			i += 2 + end
+
+			continue
+		}
+
+		j := i + 1
+		for j < len(val) && isEnvNameByte(val[j]) {
+			j++
+		}
+
+		if j == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+
+		b.WriteString(lookupVar(val[i+1:j], local))
+		i = j - 1
+	}
+
+	return b.String()
+}
+
+// resolveExpr resolves the body of a ${...} expansion, honoring the
+// ":-default" fallback form.
+func resolveExpr(expr string, local map[string]string) string {
+	if name, def, ok := strings.Cut(expr, ":-"); ok {
+		if v := lookupVar(name, local); v != "" {
+			return v
+		}
+
+		return def
+	}
+
+	return lookupVar(expr, local)
+}
+
+// lookupVar resolves a variable reference against values already set
+// earlier in the same file, then against the process environment.
+func lookupVar(name string, local map[string]string) string {
+	if v, ok := local[name]; ok {
+		return v
+	}
+
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+
+	return ""
+}
+
+// isEnvNameByte reports whether b can appear in a bare $VAR reference.
+func isEnvNameByte(b byte) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || ('0' <= b && b <= '9')
+}
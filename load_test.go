@@ -0,0 +1,205 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		vars     map[string]string
+		contents string
+		wantErr  error
+		want     map[string]string
+	}{
+		"Valid environment file": {
+			vars:     map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
+			contents: "HOME=/home/test\nDEFAULT=default\nEMPTY=10\nNESTED_VALUE=nested\n",
+			want:     map[string]string{"HOME": "/home/test", "DEFAULT": "default", "EMPTY": "10", "NESTED_VALUE": "nested"},
+		},
+		"Empty variables": {
+			vars:     map[string]string{"": ""},
+			contents: "=\n",
+			wantErr:  errors.New("setting []: setenv: The parameter is incorrect"),
+		},
+		"Does not override already-set variables": {
+			vars:     map[string]string{"HOME": "/home/preset"},
+			contents: "HOME=/home/from-file\n",
+			want:     map[string]string{"HOME": "/home/preset"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, teardown, err := setupEnvFile(t, tc.contents)
+			require.NoError(t, err)
+			defer teardown()
+
+			teardown, err = setupEnv(t, tc.vars)
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			defer teardown()
+
+			err = Load(file.Name())
+			if tc.wantErr != nil {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			for k, v := range tc.want {
+				require.Equal(t, v, os.Getenv(k))
+			}
+		})
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	require.NoError(t, Load("testdata/does-not-exist.env"))
+}
+
+func TestLoadWithOptions(t *testing.T) {
+	tests := map[string]struct {
+		preset      map[string]string
+		contents    string
+		opts        LoadOptions
+		want        map[string]string
+		wantErrText string
+	}{
+		"Overload replaces already-set variables": {
+			preset:   map[string]string{"HOME": "/home/preset"},
+			contents: "HOME=/home/from-file\n",
+			opts:     LoadOptions{Overload: true},
+			want:     map[string]string{"HOME": "/home/from-file"},
+		},
+		"Quoted double value with escapes": {
+			contents: `MSG_ESCAPES="line one\nline two"` + "\n",
+			want:     map[string]string{"MSG_ESCAPES": "line one\nline two"},
+		},
+		"Single-quoted value is literal": {
+			contents: `MSG_LITERAL='no $EXPANSION here'` + "\n",
+			preset:   map[string]string{"EXPANSION": "should-not-appear"},
+			want:     map[string]string{"MSG_LITERAL": "no $EXPANSION here"},
+		},
+		"Multiline double-quoted value": {
+			contents: "MSG_MULTILINE=\"first\nsecond\"\n",
+			want:     map[string]string{"MSG_MULTILINE": "first\nsecond"},
+		},
+		"Export prefix and inline comment": {
+			contents: "export GREETING=hello # a comment\n",
+			want:     map[string]string{"GREETING": "hello"},
+		},
+		"Variable expansion against earlier file value": {
+			contents: "LOCAL_HOST=localhost\nLOCAL_URL=http://${LOCAL_HOST}:8080\n",
+			want:     map[string]string{"LOCAL_URL": "http://localhost:8080"},
+		},
+		"Variable expansion falls back to default": {
+			contents: "FALLBACK_PORT=${FALLBACK_PORT:-8080}\n",
+			want:     map[string]string{"FALLBACK_PORT": "8080"},
+		},
+		"Variable expansion against process environment": {
+			preset:   map[string]string{"BASE_URL": "https://example.com"},
+			contents: "API_URL=$BASE_URL/api\n",
+			want:     map[string]string{"API_URL": "https://example.com/api"},
+		},
+		"DisableExpansion leaves references untouched": {
+			contents: "RAW_URL=http://${DISABLED_HOST}:8080\n",
+			opts:     LoadOptions{DisableExpansion: true},
+			want:     map[string]string{"RAW_URL": "http://${DISABLED_HOST}:8080"},
+		},
+		"Unterminated quote is an error": {
+			contents:    `MSG="unterminated` + "\n",
+			wantErrText: "unterminated quoted value",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			file, teardown, err := setupEnvFile(t, tc.contents)
+			require.NoError(t, err)
+			defer teardown()
+
+			presetTeardown, err := setupEnv(t, tc.preset)
+			require.NoError(t, err)
+			defer presetTeardown()
+
+			defer func() {
+				for k := range tc.want {
+					_ = os.Unsetenv(k)
+				}
+			}()
+
+			err = LoadWithOptions(file.Name(), tc.opts)
+			if tc.wantErrText != "" {
+				require.ErrorContains(t, err, tc.wantErrText)
+				return
+			}
+
+			require.NoError(t, err)
+			for k, v := range tc.want {
+				require.Equal(t, v, os.Getenv(k))
+			}
+		})
+	}
+}
+
+func TestStripInlineComment(t *testing.T) {
+	tests := map[string]struct {
+		have string
+		want string
+	}{
+		"no comment":                  {"localhost", "localhost"},
+		"trailing comment":            {"localhost # the host", "localhost"},
+		"comment with no space after": {"localhost #comment", "localhost"},
+		"leading comment":             {"# just a comment", ""},
+		"hash in hex color":           {"#336699", "#336699"},
+		"hash in url fragment":        {"http://host/cb#token", "http://host/cb#token"},
+		"hash preceded by tab":        {"value\t# comment", "value"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, stripInlineComment(tc.have))
+		})
+	}
+}
+
+func TestLoadWithOptions_IgnoreMissing(t *testing.T) {
+	err := LoadWithOptions("testdata/does-not-exist.env", LoadOptions{IgnoreMissing: true})
+	require.NoError(t, err)
+
+	err = LoadWithOptions("testdata/does-not-exist.env", LoadOptions{})
+	require.Error(t, err)
+}
+
+func setupEnvFile(t *testing.T, contents string) (*os.File, func(), error) {
+	t.Helper()
+
+	file, err := os.CreateTemp(os.TempDir(), "test.env")
+	if err != nil {
+		return nil, func() {}, err
+	}
+
+	if _, err := file.WriteString(contents); err != nil {
+		return nil, nil, err
+	}
+
+	teardown := func() {
+		if err := file.Close(); err != nil {
+			t.Error("setupEnvFile: close:", err)
+		}
+
+		if err := os.RemoveAll(file.Name()); err != nil {
+			t.Error("setupEnvFile: teardown:", err)
+		}
+	}
+
+	return file, teardown, nil
+}
@@ -0,0 +1,85 @@
+package env
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy derives the environment variable key for a field's
+// struct path (e.g. []string{"Database", "URL"}). The built-in strategies
+// split each path segment into words -- handling camelCase, acronyms
+// ("HTTPPort" -> "HTTP", "Port") and digit boundaries ("Http2Port" ->
+// "Http", "2", "Port") -- apply a case, and join everything with a fixed
+// separator. A custom strategy receives the full path and has complete
+// control over the result.
+type NamingStrategy func(path []string) string
+
+// SnakeUpper joins words with "_" and uppercases them, e.g.
+// "HTTPPort" -> "HTTP_PORT". It is used when a Loader has no NamingStrategy
+// of its own.
+var SnakeUpper NamingStrategy = wordStrategy("_", strings.ToUpper)
+
+// SnakeLower joins words with "_" and lowercases them, e.g.
+// "HTTPPort" -> "http_port".
+var SnakeLower NamingStrategy = wordStrategy("_", strings.ToLower)
+
+// KebabUpper joins words with "-" and uppercases them, e.g.
+// "HTTPPort" -> "HTTP-PORT".
+var KebabUpper NamingStrategy = wordStrategy("-", strings.ToUpper)
+
+// AsIs joins path segments with "_" without splitting words or changing
+// case, e.g. []string{"Database", "URL"} -> "Database_URL".
+var AsIs NamingStrategy = func(path []string) string {
+	return strings.Join(path, "_")
+}
+
+// wordStrategy returns a NamingStrategy that splits every path segment
+// into words, applies transform to each, and joins both the words within
+// a segment and the segments themselves with sep.
+func wordStrategy(sep string, transform func(string) string) NamingStrategy {
+	return func(path []string) string {
+		parts := make([]string, len(path))
+
+		for i, p := range path {
+			words := splitWords(p)
+			for j, w := range words {
+				words[j] = transform(w)
+			}
+
+			parts[i] = strings.Join(words, sep)
+		}
+
+		return strings.Join(parts, sep)
+	}
+}
+
+// splitWords splits a CamelCase or PascalCase identifier into its
+// constituent words, keeping runs of uppercase letters (acronyms)
+// together until the last one that starts a new word, and treating a
+// digit run as a word of its own: "HTTPPort" -> ["HTTP", "Port"],
+// "Http2Port" -> ["Http", "2", "Port"], "ID" -> ["ID"].
+func splitWords(s string) []string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return nil
+	}
+
+	var words []string
+
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+
+		boundary := unicode.IsDigit(prev) != unicode.IsDigit(cur) ||
+			(unicode.IsUpper(cur) && !unicode.IsUpper(prev) && !unicode.IsDigit(prev)) ||
+			(unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+
+		if boundary {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,55 @@
+package env
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := map[string]struct {
+		have string
+		want []string
+	}{
+		"pascal case":    {"PascalCase", []string{"Pascal", "Case"}},
+		"camel case":     {"camelCase", []string{"camel", "Case"}},
+		"single word":    {"Port", []string{"Port"}},
+		"acronym run":    {"HTTPPort", []string{"HTTP", "Port"}},
+		"acronym at end": {"UserID", []string{"User", "ID"}},
+		"all uppercase":  {"ID", []string{"ID"}},
+		"digit boundary": {"Http2Port", []string{"Http", "2", "Port"}},
+		"trailing digit": {"Port8080", []string{"Port", "8080"}},
+		"empty":          {"", nil},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, splitWords(tc.have))
+		})
+	}
+}
+
+func TestNamingStrategies(t *testing.T) {
+	path := []string{"Database", "HTTPPort"}
+
+	tests := map[string]struct {
+		strategy NamingStrategy
+		want     string
+	}{
+		"snake upper": {SnakeUpper, "DATABASE_HTTP_PORT"},
+		"snake lower": {SnakeLower, "database_http_port"},
+		"kebab upper": {KebabUpper, "DATABASE-HTTP-PORT"},
+		"as is":       {AsIs, "Database_HTTPPort"},
+		"custom": {
+			NamingStrategy(func(path []string) string { return strings.ToLower(strings.Join(path, ".")) }),
+			"database.httpport",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.want, tc.strategy(path))
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package env
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Source is a configuration provider consulted by a Loader. path is the
+// chain of struct field names leading to the value being resolved (e.g.
+// []string{"Database", "URL"} for a nested field); flat sources such as
+// OSEnv join it into a single key, while hierarchical sources such as
+// JSONFile walk their document by path segment so nested structs can be
+// populated without flattening to SNAKE_CASE.
+type Source interface {
+	Lookup(path []string) (string, bool)
+}
+
+// ErrSource is implemented by sources that can fail independently of any
+// particular lookup, such as file-based sources that must first read and
+// decode their backing file. A Loader checks Err on every source before
+// resolving any field.
+type ErrSource interface {
+	Err() error
+}
+
+// RawSource is implemented by flat sources that can resolve an explicit
+// `env` tag directly, without running it through the struct-path name
+// derivation that flatKey applies to a field path. Hierarchical sources
+// such as JSONFile need no such override: matchKey already matches a tag
+// used as a single path segment case-insensitively.
+type RawSource interface {
+	LookupRaw(key string) (string, bool)
+}
+
+// PathSource is implemented by a source whose association with a field is
+// established independently of any name, such as flagSource, which
+// registerFlags ties to a field by its struct path at registration time.
+// lookupField always tries LookupPath for such a source, even when the
+// field also carries an explicit `env` tag that would otherwise make it
+// skip straight to the next source.
+type PathSource interface {
+	LookupPath(path []string) (string, bool)
+}
+
+// Precedence controls which source wins when more than one provides a
+// value for the same field.
+type Precedence int
+
+const (
+	// FirstWins keeps the value from the first source in the chain that
+	// has it. This is the default.
+	FirstWins Precedence = iota
+
+	// LastWins keeps the value from the last source in the chain that has
+	// it, so later sources override earlier ones.
+	LastWins
+)
+
+// Loader parses a struct from an ordered chain of sources.
+type Loader struct {
+	sources    []Source
+	precedence Precedence
+	parsers    map[reflect.Type]func(string) (any, error)
+	naming     NamingStrategy
+	prefix     string
+	separator  string
+	flagSet    *flag.FlagSet
+}
+
+// New creates a Loader with no sources and FirstWins precedence.
+func New() *Loader {
+	return &Loader{}
+}
+
+// From appends sources to the end of the loader's chain and returns the
+// loader so calls can be chained.
+func (l *Loader) From(sources ...Source) *Loader {
+	l.sources = append(l.sources, sources...)
+	return l
+}
+
+// WithPrecedence sets how the loader resolves a field present in more than
+// one source and returns the loader so calls can be chained.
+func (l *Loader) WithPrecedence(p Precedence) *Loader {
+	l.precedence = p
+	return l
+}
+
+// WithNaming sets the strategy used to derive an environment variable key
+// from a field's struct path when it has no explicit `env` tag, and
+// returns the loader so calls can be chained. SnakeUpper is used if this
+// is never called.
+func (l *Loader) WithNaming(strategy NamingStrategy) *Loader {
+	l.naming = strategy
+	return l
+}
+
+// WithPrefix sets a prefix joined onto every derived key with the
+// loader's separator (default "_"), e.g. WithPrefix("APP") turns
+// "DATABASE_URL" into "APP_DATABASE_URL". It does not affect explicit
+// `env` tags. Returns the loader so calls can be chained.
+func (l *Loader) WithPrefix(prefix string) *Loader {
+	l.prefix = prefix
+	return l
+}
+
+// WithSeparator sets the string joined between a Prefix and a derived key.
+// It defaults to "_" and has no effect without a Prefix. Returns the
+// loader so calls can be chained.
+func (l *Loader) WithSeparator(sep string) *Loader {
+	l.separator = sep
+	return l
+}
+
+// WithFlagSet sets the *flag.FlagSet that ParseFlags registers flags on
+// and parses, instead of one created from os.Args[0]. Useful when the
+// caller already owns a FlagSet, e.g. for a subcommand. Returns the loader
+// so calls can be chained.
+func (l *Loader) WithFlagSet(fs *flag.FlagSet) *Loader {
+	l.flagSet = fs
+	return l
+}
+
+// RegisterParser registers a parser for a type setFieldValue does not
+// otherwise support, such as url.URL, net.IP or time.Time, and returns the
+// loader so calls can be chained. It takes priority over the built-in
+// kinds but not over an Unmarshaler implementation.
+func (l *Loader) RegisterParser(t reflect.Type, parse func(string) (any, error)) *Loader {
+	if l.parsers == nil {
+		l.parsers = make(map[reflect.Type]func(string) (any, error))
+	}
+
+	l.parsers[t] = parse
+
+	return l
+}
+
+// Parse fills dst from the loader's sources, falling back to each field's
+// `default` tag when no source has a value.
+func (l *Loader) Parse(dst any) error {
+	if err := l.checkSourceErrs(); err != nil {
+		return err
+	}
+
+	return l.parse(dst, orderedSources(l.sources, l.precedence))
+}
+
+// ParseFlags registers a flag.FlagSet entry for every field tagged
+// `flag:"name,alias"` (see `usage` for its help text), parses args, then
+// fills dst with precedence flags > the loader's own sources, in the
+// order From and WithPrecedence established > each field's `default` tag.
+// It uses the FlagSet set by WithFlagSet, or creates one from os.Args[0].
+// Conversion for every supported field kind, including the slice, map and
+// custom types from RegisterParser, is shared with the other sources via
+// setFieldValue.
+func (l *Loader) ParseFlags(args []string, dst any) error {
+	if err := l.checkSourceErrs(); err != nil {
+		return err
+	}
+
+	fs := l.flagSet
+	if fs == nil {
+		fs = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	}
+
+	values := make(map[string]*flagValue)
+	if err := registerFlags(fs, reflect.ValueOf(dst).Elem(), nil, values, l.parsers); err != nil {
+		return err
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sources := append([]Source{&flagSource{values: values}}, orderedSources(l.sources, l.precedence)...)
+
+	return l.parse(dst, sources)
+}
+
+// checkSourceErrs returns the first error reported by an ErrSource among
+// the loader's own sources, such as a file-based source that failed to
+// read or decode its backing file.
+func (l *Loader) checkSourceErrs() error {
+	for _, s := range l.sources {
+		if es, ok := s.(ErrSource); ok {
+			if err := es.Err(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parse fills dst by walking sources in the given order, the chain Parse
+// and ParseFlags each assemble differently.
+func (l *Loader) parse(dst any, sources []Source) error {
+	cfg := &parseConfig{
+		sources:   sources,
+		parsers:   l.parsers,
+		naming:    l.naming,
+		prefix:    l.prefix,
+		separator: l.separator,
+	}
+
+	if errs := parseToSources(dst, nil, cfg); len(errs) > 0 {
+		return &ParseError{Fields: errs}
+	}
+
+	return nil
+}
+
+// orderedSources returns sources in the order Lookup should try them for
+// the given precedence, without mutating the loader's own slice.
+func orderedSources(sources []Source, precedence Precedence) []Source {
+	if precedence != LastWins {
+		return sources
+	}
+
+	reversed := make([]Source, len(sources))
+	for i, s := range sources {
+		reversed[len(sources)-1-i] = s
+	}
+
+	return reversed
+}
+
+// parseConfig bundles the configuration threaded through parseToSources and
+// lookupField for a single Parse call.
+type parseConfig struct {
+	sources   []Source
+	parsers   map[reflect.Type]func(string) (any, error)
+	naming    NamingStrategy
+	prefix    string
+	separator string
+}
+
+// parseToSources fills the struct fields at dst, walking sources in order
+// for each field and falling back to the `default` tag. It accumulates a
+// FieldError per failing field instead of stopping at the first one, so a
+// caller sees every problem with a struct in one pass.
+func parseToSources(dst any, path []string, cfg *parseConfig) []FieldError {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+
+	var errs []FieldError
+
+	for i := 0; i < t.NumField(); i++ {
+		fieldVal := v.Field(i)
+		fieldType := t.Field(i)
+
+		if fieldType.PkgPath != "" {
+			// unexported field: can't be set via reflection, and recursing
+			// into one (e.g. time.Time's wall/ext) would panic trying to
+			// take its address as an interface.
+			continue
+		}
+
+		fieldPath := append(append([]string{}, path...), fieldType.Name)
+
+		if fieldType.Type.Kind() == reflect.Struct && !isLeafStruct(fieldType.Type, fieldVal, cfg.parsers) {
+			// recursive call is needed for nested structs.
+			errs = append(errs, parseToSources(fieldVal.Addr().Interface(), fieldPath, cfg)...)
+			continue
+		}
+
+		val, found := lookupField(fieldType, fieldPath, cfg)
+
+		val, err := resolveValue(fieldType, val)
+		if err != nil {
+			errs = append(errs, FieldError{Name: fieldType.Name, Kind: tagFile, Cause: err})
+			continue
+		}
+
+		if kind, err := checkConstraints(fieldType, val, found); err != nil {
+			errs = append(errs, FieldError{Name: fieldType.Name, Kind: kind, Cause: err})
+			continue
+		}
+
+		if err := setFieldValue(fieldType, fieldVal, val, cfg.parsers); err != nil {
+			errs = append(errs, FieldError{Name: fieldType.Name, Kind: "parse", Cause: err})
+		}
+	}
+
+	return errs
+}
+
+// isLeafStruct reports whether a struct-kind field should be treated as a
+// single value instead of being recursed into, because it has a registered
+// parser, implements Unmarshaler itself (e.g. time.Time, url.URL), or has
+// fields but none of them exported (e.g. time.Time without a registered
+// parser), in which case setFieldValue rejects it as unsupported.
+func isLeafStruct(t reflect.Type, v reflect.Value, parsers map[reflect.Type]func(string) (any, error)) bool {
+	if _, ok := parsers[t]; ok {
+		return true
+	}
+
+	if v.CanAddr() {
+		if _, ok := v.Addr().Interface().(Unmarshaler); ok {
+			return true
+		}
+	}
+
+	if t.NumField() == 0 {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// lookupField resolves a field's value from sources in order. A field with
+// an `env` tag is looked up only by its comma-separated alias names
+// (literally, with no name derivation), plus by its struct path in a
+// PathSource such as flagSource; a field without one falls back to
+// its struct path run through cfg's naming strategy. The `default` tag is
+// used if no source has it, in which case found is false so a `required`
+// directive can still flag the field.
+func lookupField(fieldType reflect.StructField, path []string, cfg *parseConfig) (val string, found bool) {
+	names := envNames(fieldType)
+	derived := deriveKey(path, cfg)
+
+	for _, src := range cfg.sources {
+		rs, isRaw := src.(RawSource)
+
+		if len(names) > 0 {
+			for _, name := range names {
+				if isRaw {
+					if val, ok := rs.LookupRaw(name); ok {
+						return val, true
+					}
+
+					continue
+				}
+
+				if val, ok := src.Lookup([]string{name}); ok {
+					return val, true
+				}
+			}
+
+			if ps, ok := src.(PathSource); ok {
+				if val, ok := ps.LookupPath(path); ok {
+					return val, true
+				}
+			}
+
+			continue
+		}
+
+		if isRaw {
+			if val, ok := rs.LookupRaw(derived); ok {
+				return val, true
+			}
+
+			continue
+		}
+
+		if val, ok := src.Lookup(path); ok {
+			return val, true
+		}
+	}
+
+	return fieldType.Tag.Get(tagDefault), false
+}
+
+// envNames returns the field's `env` tag split into its comma-separated
+// aliases, tried in order within each source, first-found-wins; a field
+// with aliases is never looked up by its derived name. A field without an
+// `env` tag has no aliases.
+func envNames(fieldType reflect.StructField) []string {
+	tag := fieldType.Tag.Get(tagEnv)
+	if tag == "" {
+		return nil
+	}
+
+	names := strings.Split(tag, ",")
+	for i, name := range names {
+		names[i] = strings.TrimSpace(name)
+	}
+
+	return names
+}
+
+// deriveKey computes the environment variable key for path using cfg's
+// naming strategy (SnakeUpper by default), then joins cfg's prefix onto it
+// with cfg's separator (default "_") if a prefix is set.
+func deriveKey(path []string, cfg *parseConfig) string {
+	naming := cfg.naming
+	if naming == nil {
+		naming = SnakeUpper
+	}
+
+	key := naming(path)
+	if cfg.prefix == "" {
+		return key
+	}
+
+	sep := cfg.separator
+	if sep == "" {
+		sep = "_"
+	}
+
+	return cfg.prefix + sep + key
+}
+
+// flatKey joins a field path into the SNAKE_CASE key used when a flat
+// source such as OSEnv, DotEnv or Map is consulted directly, outside of a
+// Loader. A Loader instead derives its own key via NamingStrategy, Prefix
+// and Separator, and resolves it through RawSource.
+func flatKey(path []string) string {
+	return SnakeUpper(path)
+}
@@ -0,0 +1,192 @@
+package env
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sourceConfig struct {
+	Host   string `env:"HOST"`
+	Port   int    `default:"8080"`
+	Nested struct {
+		Value string `notEmpty:"true"`
+	}
+}
+
+func TestLoader_Parse(t *testing.T) {
+	tests := map[string]struct {
+		sources    []Source
+		precedence Precedence
+		want       *sourceConfig
+		wantErr    string
+	}{
+		"single map source": {
+			sources: []Source{Map(map[string]string{"HOST": "localhost", "PORT": "9090", "NESTED_VALUE": "nested"})},
+			want: &sourceConfig{Host: "localhost", Port: 9090, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
+		},
+		"falls back to default tag": {
+			sources: []Source{Map(map[string]string{"HOST": "localhost", "NESTED_VALUE": "nested"})},
+			want: &sourceConfig{Host: "localhost", Port: 8080, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
+		},
+		"missing nested value is an error": {
+			sources: []Source{Map(map[string]string{"HOST": "localhost"})},
+			wantErr: "notEmpty",
+		},
+		"first source wins by default": {
+			sources: []Source{
+				Map(map[string]string{"HOST": "first", "NESTED_VALUE": "nested"}),
+				Map(map[string]string{"HOST": "second"}),
+			},
+			want: &sourceConfig{Host: "first", Port: 8080, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
+		},
+		"last wins overrides earlier sources": {
+			sources: []Source{
+				Map(map[string]string{"HOST": "first", "NESTED_VALUE": "nested"}),
+				Map(map[string]string{"HOST": "second"}),
+			},
+			precedence: LastWins,
+			want: &sourceConfig{Host: "second", Port: 8080, Nested: struct {
+				Value string `notEmpty:"true"`
+			}{"nested"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg sourceConfig
+			err := New().From(tc.sources...).WithPrecedence(tc.precedence).Parse(&cfg)
+
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, &cfg)
+		})
+	}
+}
+
+type aliasConfig struct {
+	DatabaseURL string `env:"DATABASE_URL,DB_URL,PG_URL"`
+}
+
+func TestLoader_Parse_EnvAliases(t *testing.T) {
+	tests := map[string]struct {
+		vars map[string]string
+		want string
+	}{
+		"primary name":            {map[string]string{"DATABASE_URL": "primary"}, "primary"},
+		"first alias":             {map[string]string{"DB_URL": "alias"}, "alias"},
+		"second alias":            {map[string]string{"PG_URL": "alias2"}, "alias2"},
+		"primary wins over alias": {map[string]string{"DATABASE_URL": "primary", "DB_URL": "alias"}, "primary"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg aliasConfig
+			err := New().From(Map(tc.vars)).Parse(&cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, cfg.DatabaseURL)
+		})
+	}
+}
+
+func TestLoader_Parse_EnvTagIsExclusive(t *testing.T) {
+	type config struct {
+		Token string `env:"SECRET_TOKEN"`
+	}
+
+	var cfg config
+	err := New().From(Map(map[string]string{"TOKEN": "leaked"})).Parse(&cfg)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Token, "an env tag must not fall back to the derived name in the same source")
+}
+
+func TestLoader_Parse_UnregisteredUnexportedStruct(t *testing.T) {
+	type config struct {
+		CreatedAt time.Time
+	}
+
+	var cfg config
+	err := New().From(Map(map[string]string{"CREATED_AT": "x"})).Parse(&cfg)
+	require.Error(t, err, "a struct with unexported fields and no registered parser should fail, not panic")
+}
+
+type namingConfig struct {
+	HTTPPort int
+}
+
+func TestLoader_Parse_Naming(t *testing.T) {
+	tests := map[string]struct {
+		loader *Loader
+		vars   map[string]string
+		want   int
+	}{
+		"default snake upper": {
+			loader: New(),
+			vars:   map[string]string{"HTTP_PORT": "80"},
+			want:   80,
+		},
+		"snake lower": {
+			loader: New().WithNaming(SnakeLower),
+			vars:   map[string]string{"http_port": "81"},
+			want:   81,
+		},
+		"kebab upper": {
+			loader: New().WithNaming(KebabUpper),
+			vars:   map[string]string{"HTTP-PORT": "82"},
+			want:   82,
+		},
+		"prefix with default separator": {
+			loader: New().WithPrefix("APP"),
+			vars:   map[string]string{"APP_HTTP_PORT": "83"},
+			want:   83,
+		},
+		"prefix with custom separator": {
+			loader: New().WithPrefix("APP").WithSeparator("."),
+			vars:   map[string]string{"APP.HTTP_PORT": "84"},
+			want:   84,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg namingConfig
+			err := tc.loader.From(Map(tc.vars)).Parse(&cfg)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, cfg.HTTPPort)
+		})
+	}
+}
+
+type erroringSource struct{ err error }
+
+func (s erroringSource) Lookup([]string) (string, bool) { return "", false }
+func (s erroringSource) Err() error                     { return s.err }
+
+func TestLoader_Parse_SourceError(t *testing.T) {
+	var cfg sourceConfig
+	err := New().From(erroringSource{err: errors.New("boom")}).Parse(&cfg)
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestMap(t *testing.T) {
+	src := Map(map[string]string{"NESTED_VALUE": "nested"})
+
+	val, ok := src.Lookup([]string{"Nested", "Value"})
+	require.True(t, ok)
+	require.Equal(t, "nested", val)
+
+	_, ok = src.Lookup([]string{"Missing"})
+	require.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package env
+
+import (
+	"os"
+	"sync"
+)
+
+// OSEnv returns a Source that resolves values from the process
+// environment.
+func OSEnv() Source {
+	return osEnvSource{}
+}
+
+type osEnvSource struct{}
+
+func (osEnvSource) Lookup(path []string) (string, bool) {
+	return os.LookupEnv(flatKey(path))
+}
+
+func (osEnvSource) LookupRaw(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// DotEnv returns a Source that parses path the same way Load does, into
+// its own private map, so it only ever resolves keys the file itself
+// defines rather than the wider process environment; use OSEnv for that.
+// The file is read at most once, on the first Lookup or Err call.
+func DotEnv(path string) Source {
+	return &dotEnvSource{path: path}
+}
+
+type dotEnvSource struct {
+	path string
+	once sync.Once
+	err  error
+	vars map[string]string
+}
+
+func (s *dotEnvSource) load() {
+	s.once.Do(func() {
+		s.vars, s.err = parseDotEnv(s.path, LoadOptions{IgnoreMissing: true})
+	})
+}
+
+// Err reports any error from loading the dotenv file.
+func (s *dotEnvSource) Err() error {
+	s.load()
+	return s.err
+}
+
+func (s *dotEnvSource) Lookup(path []string) (string, bool) {
+	s.load()
+	if s.err != nil {
+		return "", false
+	}
+
+	v, ok := s.vars[flatKey(path)]
+
+	return v, ok
+}
+
+func (s *dotEnvSource) LookupRaw(key string) (string, bool) {
+	s.load()
+	if s.err != nil {
+		return "", false
+	}
+
+	v, ok := s.vars[key]
+
+	return v, ok
+}
+
+// Map returns a Source backed by an explicit map of already SNAKE_CASE-d
+// keys to values, useful for tests or values gathered from elsewhere in a
+// program.
+func Map(values map[string]string) Source {
+	return mapSource(values)
+}
+
+type mapSource map[string]string
+
+func (m mapSource) Lookup(path []string) (string, bool) {
+	v, ok := m[flatKey(path)]
+	return v, ok
+}
+
+func (m mapSource) LookupRaw(key string) (string, bool) {
+	v, ok := m[key]
+	return v, ok
+}
@@ -0,0 +1,141 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// JSONFile returns a hierarchical Source that decodes path as JSON. A
+// missing file is treated as empty, not an error.
+func JSONFile(path string) Source {
+	return newFileSource(path, json.Unmarshal)
+}
+
+// YAMLFile returns a hierarchical Source that decodes path as YAML. A
+// missing file is treated as empty, not an error.
+func YAMLFile(path string) Source {
+	return newFileSource(path, yaml.Unmarshal)
+}
+
+// TOMLFile returns a hierarchical Source that decodes path as TOML. A
+// missing file is treated as empty, not an error.
+func TOMLFile(path string) Source {
+	return newFileSource(path, toml.Unmarshal)
+}
+
+// fileSource is the lazy-load-and-cache machinery shared by the
+// hierarchical file sources. Unlike the flat sources, it resolves a field
+// path by walking the decoded document instead of joining it into a single
+// SNAKE_CASE key, so nested structs map naturally onto nested documents.
+type fileSource struct {
+	path   string
+	decode func([]byte, any) error
+
+	once sync.Once
+	data map[string]any
+	err  error
+}
+
+func newFileSource(path string, decode func([]byte, any) error) *fileSource {
+	return &fileSource{path: path, decode: decode}
+}
+
+func (s *fileSource) load() {
+	s.once.Do(func() {
+		b, err := os.ReadFile(s.path)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				s.err = fmt.Errorf("reading %s: %w", s.path, err)
+			}
+
+			return
+		}
+
+		var data map[string]any
+		if err := s.decode(b, &data); err != nil {
+			s.err = fmt.Errorf("decoding %s: %w", s.path, err)
+			return
+		}
+
+		s.data = data
+	})
+}
+
+// Err reports any error from reading or decoding the file.
+func (s *fileSource) Err() error {
+	s.load()
+	return s.err
+}
+
+func (s *fileSource) Lookup(path []string) (string, bool) {
+	s.load()
+	if s.err != nil || s.data == nil {
+		return "", false
+	}
+
+	return lookupNode(s.data, path)
+}
+
+// lookupNode walks a decoded document by path, matching each segment
+// case-insensitively against map keys, and stringifies the leaf value.
+func lookupNode(node any, path []string) (string, bool) {
+	for i, key := range path {
+		m, ok := node.(map[string]any)
+		if !ok {
+			return "", false
+		}
+
+		v, ok := matchKey(m, key)
+		if !ok {
+			return "", false
+		}
+
+		if i == len(path)-1 {
+			return stringify(v)
+		}
+
+		node = v
+	}
+
+	return "", false
+}
+
+// matchKey looks up key in m, falling back to a case-insensitive match
+// against the key itself and its SNAKE_CASE form so struct field names
+// line up with the conventional casing of JSON/YAML/TOML documents.
+func matchKey(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) || strings.EqualFold(k, camelToSnake(key)) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// stringify renders a decoded leaf value as the string setFieldValue
+// expects.
+func stringify(v any) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return val, true
+	case fmt.Stringer:
+		return val.String(), true
+	default:
+		return fmt.Sprintf("%v", val), true
+	}
+}
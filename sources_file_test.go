@@ -0,0 +1,85 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSources(t *testing.T) {
+	tests := map[string]struct {
+		ext      string
+		contents string
+		newSrc   func(path string) Source
+	}{
+		"JSONFile": {
+			ext:      ".json",
+			contents: `{"host": "localhost", "database": {"url": "postgres://db"}}`,
+			newSrc:   JSONFile,
+		},
+		"YAMLFile": {
+			ext:      ".yaml",
+			contents: "host: localhost\ndatabase:\n  url: postgres://db\n",
+			newSrc:   YAMLFile,
+		},
+		"TOMLFile": {
+			ext:      ".toml",
+			contents: "host = \"localhost\"\n\n[database]\nurl = \"postgres://db\"\n",
+			newSrc:   TOMLFile,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config"+tc.ext)
+			require.NoError(t, os.WriteFile(path, []byte(tc.contents), 0o600))
+
+			src := tc.newSrc(path)
+
+			val, ok := src.Lookup([]string{"Host"})
+			require.True(t, ok)
+			require.Equal(t, "localhost", val)
+
+			val, ok = src.Lookup([]string{"Database", "URL"})
+			require.True(t, ok)
+			require.Equal(t, "postgres://db", val)
+
+			_, ok = src.Lookup([]string{"Missing"})
+			require.False(t, ok)
+
+			if es, ok := src.(ErrSource); ok {
+				require.NoError(t, es.Err())
+			}
+		})
+	}
+}
+
+func TestFileSource_MissingFileIsNotAnError(t *testing.T) {
+	src := JSONFile(filepath.Join(t.TempDir(), "does-not-exist.json"))
+
+	_, ok := src.Lookup([]string{"Host"})
+	require.False(t, ok)
+	require.NoError(t, src.(ErrSource).Err())
+}
+
+func TestFileSource_DecodeError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	src := JSONFile(path)
+	require.ErrorContains(t, src.(ErrSource).Err(), "decoding")
+}
+
+func TestLoader_Parse_WithFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"host": "localhost", "port": 9090, "nested": {"value": "nested"}}`), 0o600))
+
+	var cfg sourceConfig
+	err := New().From(JSONFile(path)).Parse(&cfg)
+	require.NoError(t, err)
+	require.Equal(t, &sourceConfig{Host: "localhost", Port: 9090, Nested: struct {
+		Value string `notEmpty:"true"`
+	}{"nested"}}, &cfg)
+}
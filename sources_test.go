@@ -0,0 +1,49 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDotEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("HOST=localhost\n"), 0o600))
+
+	src := DotEnv(path)
+
+	val, ok := src.Lookup([]string{"Host"})
+	require.True(t, ok)
+	require.Equal(t, "localhost", val)
+
+	_, ok = src.Lookup([]string{"Missing"})
+	require.False(t, ok)
+
+	require.NoError(t, src.(ErrSource).Err())
+}
+
+func TestDotEnv_IsolatedFromProcessEnvironment(t *testing.T) {
+	require.NoError(t, os.Setenv("LEAKED_KEY", "ambient"))
+	defer func() { _ = os.Unsetenv("LEAKED_KEY") }()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte("OTHER_KEY=value\n"), 0o600))
+
+	src := DotEnv(path)
+
+	_, ok := src.Lookup([]string{"LeakedKey"})
+	require.False(t, ok, "a DotEnv source must not answer for keys only present in the process environment")
+
+	_, ok = src.(RawSource).LookupRaw("LEAKED_KEY")
+	require.False(t, ok, "a DotEnv source must not answer for keys only present in the process environment")
+}
+
+func TestDotEnv_MissingFileIsNotAnError(t *testing.T) {
+	src := DotEnv(filepath.Join(t.TempDir(), "does-not-exist.env"))
+
+	_, ok := src.Lookup([]string{"Host"})
+	require.False(t, ok)
+	require.NoError(t, src.(ErrSource).Err())
+}
@@ -0,0 +1,104 @@
+package env
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const tagSep = "sep"
+
+// Unmarshaler is implemented by types that know how to parse themselves
+// from a single value. setFieldValue tries it before falling through to
+// the built-in kinds, so it takes priority over a registered parser.
+type Unmarshaler interface {
+	UnmarshalEnv(s string) error
+}
+
+// asUnmarshaler reports whether v implements Unmarshaler, taking its
+// address for a non-pointer field or allocating a nil pointer field so it
+// can be checked and, if it does implement Unmarshaler, populated in place.
+func asUnmarshaler(v reflect.Value) (Unmarshaler, bool) {
+	switch {
+	case v.Kind() == reflect.Ptr && v.IsNil():
+		if !v.CanSet() {
+			return nil, false
+		}
+
+		v.Set(reflect.New(v.Type().Elem()))
+	case v.Kind() != reflect.Ptr:
+		if !v.CanAddr() {
+			return nil, false
+		}
+
+		v = v.Addr()
+	}
+
+	if !v.CanInterface() {
+		return nil, false
+	}
+
+	u, ok := v.Interface().(Unmarshaler)
+
+	return u, ok
+}
+
+// setSliceValue splits val on the field's `sep` tag (default ",") and sets
+// fieldVal to a slice of any type setFieldValue supports.
+func setSliceValue(fieldType reflect.StructField, fieldVal reflect.Value, val string, parsers map[reflect.Type]func(string) (any, error)) error {
+	sep := fieldType.Tag.Get(tagSep)
+	if sep == "" {
+		sep = ","
+	}
+
+	elemType := fieldType.Type.Elem()
+	slice := reflect.MakeSlice(fieldType.Type, 0, 0)
+
+	if val == "" {
+		fieldVal.Set(slice)
+		return nil
+	}
+
+	elemField := reflect.StructField{Name: fieldType.Name, Type: elemType}
+
+	for _, part := range strings.Split(val, sep) {
+		elemVal := reflect.New(elemType).Elem()
+		if err := setFieldValue(elemField, elemVal, part, parsers); err != nil {
+			return fmt.Errorf("parsing slice element: %w", err)
+		}
+
+		slice = reflect.Append(slice, elemVal)
+	}
+
+	fieldVal.Set(slice)
+
+	return nil
+}
+
+// setMapValue parses a "KEY=V,KEY2=V2" value into a map[string]string
+// field.
+func setMapValue(fieldType reflect.Type, fieldVal reflect.Value, val string) error {
+	if fieldType.Key().Kind() != reflect.String || fieldType.Elem().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map type: %s", fieldType)
+	}
+
+	m := reflect.MakeMap(fieldType)
+
+	if val == "" {
+		fieldVal.Set(m)
+		return nil
+	}
+
+	for _, pair := range strings.Split(val, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid map entry: %q", pair)
+		}
+
+		m.SetMapIndex(reflect.ValueOf(key), reflect.ValueOf(value))
+	}
+
+	fieldVal.Set(m)
+
+	return nil
+}
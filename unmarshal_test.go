@@ -0,0 +1,166 @@
+package env
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type level struct{ n int }
+
+func (l *level) UnmarshalEnv(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("parsing level: %w", err)
+	}
+
+	l.n = n
+
+	return nil
+}
+
+func TestSetFieldValue_Unmarshaler(t *testing.T) {
+	var l level
+
+	fieldType := reflect.StructField{Name: "Level", Type: reflect.TypeOf(l)}
+	require.NoError(t, setFieldValue(fieldType, reflect.ValueOf(&l).Elem(), "3", nil))
+	require.Equal(t, 3, l.n)
+
+	require.ErrorContains(t, setFieldValue(fieldType, reflect.ValueOf(&l).Elem(), "invalid", nil), "parsing level")
+}
+
+func TestSetFieldValue_RegisteredParser(t *testing.T) {
+	parsers := map[reflect.Type]func(string) (any, error){
+		reflect.TypeOf(net.IP{}): func(s string) (any, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP: %q", s)
+			}
+
+			return ip, nil
+		},
+	}
+
+	var ip net.IP
+
+	fieldType := reflect.StructField{Name: "IP", Type: reflect.TypeOf(ip)}
+	require.NoError(t, setFieldValue(fieldType, reflect.ValueOf(&ip).Elem(), "127.0.0.1", parsers))
+	require.Equal(t, net.ParseIP("127.0.0.1"), ip)
+
+	require.ErrorContains(t, setFieldValue(fieldType, reflect.ValueOf(&ip).Elem(), "not-an-ip", parsers), "invalid IP")
+}
+
+func TestSetFieldValue_Pointer(t *testing.T) {
+	var port *int
+
+	fieldType := reflect.StructField{Name: "Port", Type: reflect.TypeOf(port)}
+	require.NoError(t, setFieldValue(fieldType, reflect.ValueOf(&port).Elem(), "8080", nil))
+	require.NotNil(t, port)
+	require.Equal(t, 8080, *port)
+}
+
+func TestSetFieldValue_Slice(t *testing.T) {
+	tests := map[string]struct {
+		field   interface{}
+		tag     reflect.StructTag
+		have    string
+		want    interface{}
+		wantErr string
+	}{
+		"Default comma separator": {
+			field: []string{}, have: "a,b,c", want: []string{"a", "b", "c"},
+		},
+		"Custom separator": {
+			field: []string{}, tag: `sep:";"`, have: "a;b;c", want: []string{"a", "b", "c"},
+		},
+		"Slice of ints": {
+			field: []int{}, have: "1,2,3", want: []int{1, 2, 3},
+		},
+		"Empty value produces empty slice": {
+			field: []string{}, have: "", want: []string{},
+		},
+		"Invalid element": {
+			field: []int{}, have: "1,x", wantErr: "parsing slice element",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fieldVal := reflect.New(reflect.TypeOf(tc.field)).Elem()
+			fieldType := reflect.StructField{Name: "Field", Type: reflect.TypeOf(tc.field), Tag: tc.tag}
+			err := setFieldValue(fieldType, fieldVal, tc.have, nil)
+
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, fieldVal.Interface())
+		})
+	}
+}
+
+func TestSetFieldValue_Map(t *testing.T) {
+	tests := map[string]struct {
+		have    string
+		want    map[string]string
+		wantErr string
+	}{
+		"Multiple entries": {
+			have: "A=1,B=2",
+			want: map[string]string{"A": "1", "B": "2"},
+		},
+		"Empty value": {
+			have: "",
+			want: map[string]string{},
+		},
+		"Missing equals sign": {
+			have:    "A",
+			wantErr: "invalid map entry",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var m map[string]string
+
+			fieldVal := reflect.ValueOf(&m).Elem()
+			fieldType := reflect.StructField{Name: "Field", Type: reflect.TypeOf(m)}
+			err := setFieldValue(fieldType, fieldVal, tc.have, nil)
+
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.want, m)
+		})
+	}
+}
+
+func TestLoader_RegisterParser(t *testing.T) {
+	type config struct {
+		IP net.IP `env:"IP"`
+	}
+
+	loader := New().
+		From(Map(map[string]string{"IP": "10.0.0.1"})).
+		RegisterParser(reflect.TypeOf(net.IP{}), func(s string) (any, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP: %q", s)
+			}
+
+			return ip, nil
+		})
+
+	var cfg config
+	require.NoError(t, loader.Parse(&cfg))
+	require.Equal(t, net.ParseIP("10.0.0.1"), cfg.IP)
+}
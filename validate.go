@@ -0,0 +1,95 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+const (
+	tagRequired = "required"
+	tagNotEmpty = "notEmpty"
+	tagExpand   = "expand"
+	tagFile     = "file"
+)
+
+// FieldError describes why a single struct field could not be resolved or
+// parsed.
+type FieldError struct {
+	Name  string
+	Kind  string
+	Cause error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field %s: %s: %v", e.Name, e.Kind, e.Cause)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Cause
+}
+
+// ParseError aggregates every field failure encountered while parsing a
+// struct, rather than stopping at the first one. Use errors.As to recover
+// it and inspect Fields, or errors.Is against a field's Cause.
+type ParseError struct {
+	Fields []FieldError
+}
+
+func (e *ParseError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ParseError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i := range e.Fields {
+		errs[i] = &e.Fields[i]
+	}
+
+	return errs
+}
+
+// resolveValue applies the `expand` and `file` tag directives to a field's
+// looked-up value, in that order: expand substitutes ${VAR}, $VAR and
+// ${VAR:-default} references against the process environment, then file
+// treats the (possibly expanded) result as a path and replaces it with the
+// contents of that file, for values such as DB_PASSWORD_FILE pointing at a
+// mounted Docker or Kubernetes secret.
+func resolveValue(fieldType reflect.StructField, val string) (string, error) {
+	if _, ok := fieldType.Tag.Lookup(tagExpand); ok {
+		val = expand(val, nil)
+	}
+
+	if _, ok := fieldType.Tag.Lookup(tagFile); ok && val != "" {
+		content, err := os.ReadFile(val)
+		if err != nil {
+			return "", fmt.Errorf("reading file: %w", err)
+		}
+
+		val = strings.TrimSpace(string(content))
+	}
+
+	return val, nil
+}
+
+// checkConstraints reports the FieldError.Kind and error for val if it
+// violates a `required` or `notEmpty` directive on fieldType. found reports
+// whether the value came from a source rather than the field's `default`
+// tag.
+func checkConstraints(fieldType reflect.StructField, val string, found bool) (kind string, err error) {
+	if _, ok := fieldType.Tag.Lookup(tagRequired); ok && !found {
+		return tagRequired, fmt.Errorf("no value in any source")
+	}
+
+	if _, ok := fieldType.Tag.Lookup(tagNotEmpty); ok && val == "" {
+		return tagNotEmpty, fmt.Errorf("value is empty")
+	}
+
+	return "", nil
+}
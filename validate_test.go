@@ -0,0 +1,91 @@
+package env
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_Parse_Required(t *testing.T) {
+	type config struct {
+		Password string `env:"PASSWORD" required:"true"`
+	}
+
+	var cfg config
+	err := New().From(Map(nil)).Parse(&cfg)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Len(t, parseErr.Fields, 1)
+	require.Equal(t, "required", parseErr.Fields[0].Kind)
+
+	cfg = config{}
+	require.NoError(t, New().From(Map(map[string]string{"PASSWORD": ""})).Parse(&cfg))
+}
+
+func TestLoader_Parse_NotEmpty(t *testing.T) {
+	type config struct {
+		Password string `env:"PASSWORD" notEmpty:"true"`
+	}
+
+	var cfg config
+	err := New().From(Map(map[string]string{"PASSWORD": ""})).Parse(&cfg)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Len(t, parseErr.Fields, 1)
+	require.Equal(t, "notEmpty", parseErr.Fields[0].Kind)
+}
+
+func TestLoader_Parse_Expand(t *testing.T) {
+	require.NoError(t, os.Setenv("EXPAND_HOST", "db.internal"))
+	defer func() { _ = os.Unsetenv("EXPAND_HOST") }()
+
+	type config struct {
+		DSN string `env:"DSN" expand:"true"`
+	}
+
+	var cfg config
+	require.NoError(t, New().From(Map(map[string]string{"DSN": "postgres://${EXPAND_HOST}/app"})).Parse(&cfg))
+	require.Equal(t, "postgres://db.internal/app", cfg.DSN)
+}
+
+func TestLoader_Parse_File(t *testing.T) {
+	dir := t.TempDir()
+	pth := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(pth, []byte("s3cr3t\n"), 0o600))
+
+	type config struct {
+		Password string `env:"PASSWORD_FILE" file:"true"`
+	}
+
+	var cfg config
+	require.NoError(t, New().From(Map(map[string]string{"PASSWORD_FILE": pth})).Parse(&cfg))
+	require.Equal(t, "s3cr3t", cfg.Password)
+
+	cfg = config{}
+	err := New().From(Map(map[string]string{"PASSWORD_FILE": filepath.Join(dir, "missing")})).Parse(&cfg)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, "file", parseErr.Fields[0].Kind)
+}
+
+func TestParseError_Aggregation(t *testing.T) {
+	type config struct {
+		Host string `env:"HOST" required:"true"`
+		Port string `env:"PORT" notEmpty:"true"`
+	}
+
+	var cfg config
+	err := New().From(Map(map[string]string{"PORT": ""})).Parse(&cfg)
+
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Len(t, parseErr.Fields, 2)
+	require.ErrorContains(t, err, "required")
+	require.ErrorContains(t, err, "notEmpty")
+}